@@ -0,0 +1,34 @@
+package rdf
+
+import (
+	"context"
+
+	"github.com/google/badwolf/storage"
+	"github.com/google/badwolf/storage/memory"
+)
+
+// Backend constructs the underlying BadWolf storage.Graph a Graph is built
+// on. It exists so rdf.Graph isn't hard-wired to memory.DefaultStore,
+// letting callers plug in alternative storage such as a disk-backed one.
+type Backend interface {
+	NewGraph(ctx context.Context, name string) (storage.Graph, error)
+	// Store returns the underlying storage.Store graphs are created on.
+	// Graph keeps this around so operations that need the store rather
+	// than a single graph, such as Query's planner, target the store a
+	// graph actually lives on instead of always assuming the default.
+	Store() storage.Store
+}
+
+// memoryBackend is the default Backend, backed by BadWolf's in-process
+// store. It is what NewGraph/NewNamedGraph have always used.
+type memoryBackend struct{}
+
+func (memoryBackend) NewGraph(ctx context.Context, name string) (storage.Graph, error) {
+	return memory.DefaultStore.NewGraph(ctx, name)
+}
+
+func (memoryBackend) Store() storage.Store {
+	return memory.DefaultStore
+}
+
+var defaultBackend Backend = memoryBackend{}
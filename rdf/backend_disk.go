@@ -0,0 +1,82 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+var triplesBucket = []byte("triples")
+
+// OpenGraph opens (creating if necessary) the BoltDB file at path and loads
+// the triples it holds into a new in-memory graph. The on-disk file stores
+// one key per triple, keyed by its canonical string, so a follow-up
+// SyncTo/OpenGraph round-trip lets awless avoid re-fetching and re-parsing
+// the full cloud inventory on every command. The returned graph is not
+// backed by the file: the file is closed before OpenGraph returns, and
+// later changes to the graph reach it only through SyncTo.
+func OpenGraph(path string) (*Graph, error) {
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rdf: could not open %s: %s", path, err)
+	}
+	defer db.Close()
+
+	g, err := NewGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(triplesBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			t, err := triple.Parse(string(k), literal.DefaultBuilder())
+			if err != nil {
+				return err
+			}
+			return g.Add(t)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// SyncTo persists the graph's current triples to path, creating the file
+// if necessary and overwriting its previous contents.
+func (g *Graph) SyncTo(path string) error {
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return fmt.Errorf("rdf: could not open %s: %s", path, err)
+	}
+	defer db.Close()
+
+	triples, err := g.allTriples(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(triplesBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		b, err := tx.CreateBucket(triplesBucket)
+		if err != nil {
+			return err
+		}
+		for _, t := range triples {
+			if err := b.Put([]byte(t.String()), []byte{1}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/badwolf/storage"
+	"github.com/google/badwolf/triple"
+)
+
+// Codec encodes and decodes triples for a particular serialization format.
+// Both directions stream through a channel rather than buffering the whole
+// graph, so multi-GB inventories don't need to fit in memory.
+type Codec interface {
+	// Encode reads triples from in until it is closed and writes their
+	// serialized form to w.
+	Encode(ctx context.Context, w io.Writer, in <-chan *triple.Triple) error
+	// Decode parses the serialized form read from r and sends the
+	// resulting triples to out, closing out once done.
+	Decode(ctx context.Context, r io.Reader, out chan<- *triple.Triple) error
+}
+
+// Quad pairs a triple with the name of the graph it belongs to, for
+// formats whose serialization addresses more than one named graph per
+// document.
+type Quad struct {
+	Graph  string
+	Triple *triple.Triple
+}
+
+// QuadCodec is a Codec whose serialization names a graph per statement,
+// such as N-Quads. Its Encode/Decode (from Codec) work against a single
+// target graph like any other codec, discarding the graph term; its
+// EncodeQuads/DecodeQuads carry that term explicitly, which is what lets
+// Graph.MarshalTo stamp a graph's own name on each statement and
+// Store.MarshalTo/UnmarshalFrom fan a single document out across several
+// named graphs instead of collapsing them into one. Graph.UnmarshalFrom
+// uses Decode, so it does not read the graph term back.
+type QuadCodec interface {
+	Codec
+	EncodeQuads(ctx context.Context, w io.Writer, in <-chan Quad) error
+	DecodeQuads(ctx context.Context, r io.Reader, out chan<- Quad) error
+}
+
+var codecs = map[string]Codec{}
+
+// RegisterCodec registers c under name so it can be selected by
+// MarshalTo/UnmarshalFrom, or sniffed from a file extension.
+func RegisterCodec(name string, c Codec) {
+	codecs[name] = c
+}
+
+func codecFor(format string) (Codec, error) {
+	c, ok := codecs[format]
+	if !ok {
+		return nil, fmt.Errorf("rdf: no codec registered for format %q", format)
+	}
+	return c, nil
+}
+
+var extensionFormats = map[string]string{
+	".bql":    "bql",
+	".nt":     "ntriples",
+	".nq":     "nquads",
+	".ttl":    "turtle",
+	".jsonld": "jsonld",
+}
+
+// sniffFormat guesses a registered codec name from a file extension,
+// falling back to the native bql line format historically used by awless.
+func sniffFormat(path string) string {
+	if format, ok := extensionFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return "bql"
+}
+
+// MarshalTo streams the graph's triples to w using the named codec. For a
+// QuadCodec, each statement is stamped with g's own name, so unmarshaling
+// it back (here or via Store.UnmarshalFrom) reproduces the same graph.
+func (g *Graph) MarshalTo(ctx context.Context, w io.Writer, format string) error {
+	c, err := codecFor(format)
+	if err != nil {
+		return err
+	}
+
+	if qc, ok := c.(QuadCodec); ok {
+		return g.marshalQuadsTo(ctx, w, qc)
+	}
+
+	triplec := make(chan *triple.Triple)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- g.Triples(ctx, storage.DefaultLookup, triplec)
+	}()
+
+	if err := c.Encode(ctx, w, triplec); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+func (g *Graph) marshalQuadsTo(ctx context.Context, w io.Writer, qc QuadCodec) error {
+	triplec := make(chan *triple.Triple)
+	quadc := make(chan Quad)
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- g.Triples(ctx, storage.DefaultLookup, triplec)
+	}()
+	go func() {
+		defer close(quadc)
+		for t := range triplec {
+			quadc <- Quad{Graph: g.name, Triple: t}
+		}
+	}()
+
+	if err := qc.EncodeQuads(ctx, w, quadc); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// UnmarshalFrom streams triples decoded from r, using the named codec,
+// straight into the graph. For a QuadCodec, the graph term each statement
+// carries is read but discarded: UnmarshalFrom always targets g alone. To
+// fan a multi-graph document out across the graphs it names, use
+// Store.UnmarshalFrom instead.
+func (g *Graph) UnmarshalFrom(ctx context.Context, r io.Reader, format string) error {
+	c, err := codecFor(format)
+	if err != nil {
+		return err
+	}
+
+	triplec := make(chan *triple.Triple)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Decode(ctx, r, triplec)
+	}()
+
+	for t := range triplec {
+		if err := g.Add(t); err != nil {
+			return err
+		}
+	}
+	return <-errc
+}
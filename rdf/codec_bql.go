@@ -0,0 +1,58 @@
+package rdf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+func init() {
+	RegisterCodec("bql", bqlCodec{})
+}
+
+// bqlCodec is the native line-based triple format BadWolf's own tools and
+// awless have always used: one triple.String() per line.
+type bqlCodec struct{}
+
+func (bqlCodec) Encode(ctx context.Context, w io.Writer, in <-chan *triple.Triple) error {
+	bw := bufio.NewWriter(w)
+	first := true
+	for t := range in {
+		if !first {
+			if _, err := bw.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := bw.WriteString(t.String()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (bqlCodec) Decode(ctx context.Context, r io.Reader, out chan<- *triple.Triple) error {
+	defer close(out)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		t, err := triple.Parse(line, literal.DefaultBuilder())
+		if err != nil {
+			return fmt.Errorf("rdf: invalid bql triple %q: %s", line, err)
+		}
+		select {
+		case out <- t:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
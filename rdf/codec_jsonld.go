@@ -0,0 +1,90 @@
+package rdf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+func init() {
+	RegisterCodec("jsonld", jsonldCodec{})
+}
+
+// jsonldNode is the flattened JSON-LD representation this codec reads and
+// writes: one object per triple, expanded form (no @context compaction).
+type jsonldNode struct {
+	ID        string `json:"@id"`
+	Predicate string `json:"@predicate"`
+	Value     string `json:"@value"`
+}
+
+// jsonldCodec implements a minimal, flattened JSON-LD encoding: a JSON
+// array of {"@id", "@predicate", "@value"} objects, one per triple. It does
+// not attempt context compaction or nested node embedding.
+type jsonldCodec struct{}
+
+func (jsonldCodec) Encode(ctx context.Context, w io.Writer, in <-chan *triple.Triple) error {
+	enc := json.NewEncoder(w)
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	for t := range in {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		s, p, o := t.Subject(), t.Predicate(), t.Object()
+		if err := enc.Encode(jsonldNode{ID: s.String(), Predicate: p.String(), Value: o.String()}); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+func (jsonldCodec) Decode(ctx context.Context, r io.Reader, out chan<- *triple.Triple) error {
+	defer close(out)
+
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("rdf: invalid json-ld document: %s", err)
+	}
+
+	// Decode one node at a time off the stream instead of json.Decoder.
+	// Decode-ing the whole array into a slice, so a multi-GB inventory
+	// doesn't need to fit in memory.
+	for dec.More() {
+		var n jsonldNode
+		if err := dec.Decode(&n); err != nil {
+			return fmt.Errorf("rdf: invalid json-ld document: %s", err)
+		}
+
+		t, err := parseJSONLDNode(n)
+		if err != nil {
+			return fmt.Errorf("rdf: invalid json-ld node %+v: %s", n, err)
+		}
+		select {
+		case out <- t:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("rdf: invalid json-ld document: %s", err)
+	}
+	return nil
+}
+
+func parseJSONLDNode(n jsonldNode) (*triple.Triple, error) {
+	return triple.Parse(fmt.Sprintf("%s %s %s", n.ID, n.Predicate, n.Value), literal.DefaultBuilder())
+}
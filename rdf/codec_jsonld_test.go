@@ -0,0 +1,32 @@
+package rdf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+)
+
+func TestJSONLDCodecDecodeStreamsNodes(t *testing.T) {
+	doc := `[
+		{"@id": "/region<us-east-1>", "@predicate": "\"has_name\"@[]", "@value": "\"prod\"^^type:text"},
+		{"@id": "/region<eu-west-1>", "@predicate": "\"has_name\"@[]", "@value": "\"staging\"^^type:text"}
+	]`
+
+	out := make(chan *triple.Triple)
+	errc := make(chan error, 1)
+	go func() { errc <- (jsonldCodec{}).Decode(context.Background(), strings.NewReader(doc), out) }()
+
+	var got []*triple.Triple
+	for tr := range out {
+		got = append(got, tr)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("decoded %d triples, want 2", len(got))
+	}
+}
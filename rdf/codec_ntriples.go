@@ -0,0 +1,92 @@
+package rdf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+)
+
+func init() {
+	RegisterCodec("ntriples", ntriplesCodec{})
+}
+
+// ntriplesCodec implements the W3C N-Triples line format: one
+// "<subject> <predicate> <object> ." statement per line.
+type ntriplesCodec struct{}
+
+func (ntriplesCodec) Encode(ctx context.Context, w io.Writer, in <-chan *triple.Triple) error {
+	bw := bufio.NewWriter(w)
+	for t := range in {
+		line, err := ntriplesLine(t)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(bw, line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (ntriplesCodec) Decode(ctx context.Context, r io.Reader, out chan<- *triple.Triple) error {
+	defer close(out)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t, err := parseNTriplesLine(line)
+		if err != nil {
+			return fmt.Errorf("rdf: invalid n-triples statement %q: %s", line, err)
+		}
+		select {
+		case out <- t:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+// ntriplesLine renders t as a standards-compliant N-Triples statement: real
+// IRIREFs for the subject and predicate (and a node or predicate object),
+// or a quoted, typed literal object.
+func ntriplesLine(t *triple.Triple) (string, error) {
+	o, err := rdfObject(t.Object())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s .", nodeIRI(t.Subject()), predicateIRI(t.Predicate()), o), nil
+}
+
+// parseNTriplesLine converts a "<s> <p> o ." line into a BadWolf triple. It
+// tokenizes with splitCodecFields rather than strings.Fields so a quoted
+// literal object containing whitespace (e.g. an AWS tag value like "my
+// bucket") isn't split mid-value.
+func parseNTriplesLine(line string) (*triple.Triple, error) {
+	fields := trimTrailingDot(splitCodecFields(line))
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("expected subject, predicate and object, got %d terms", len(fields))
+	}
+
+	s, err := parseNodeIRI(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	p, err := parsePredicateIRI(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	o, err := parseRDFObject(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return triple.New(s, p, o)
+}
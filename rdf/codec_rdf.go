@@ -0,0 +1,211 @@
+package rdf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+)
+
+// These namespaces give BadWolf's node, predicate and literal-type values a
+// real IRI form, since none of them are IRIs themselves: a node like
+// "/region<us-east-1>" or a predicate like "\"has_name\"@[]" contains
+// characters ('<', '>', '"') an IRIREF can't carry unescaped. Percent
+// encoding the pretty-printed value under one of these URNs keeps the
+// encoding lossless and round-trippable without inventing a custom syntax.
+const (
+	nodeIRIPrefix      = "urn:awless:node:"
+	predicateIRIPrefix = "urn:awless:predicate:"
+	literalIRIPrefix   = "urn:awless:literal:"
+	graphIRIPrefix     = "urn:awless:graph:"
+)
+
+// graphIRI renders a graph name (e.g. "?prod") as a real IRIREF.
+func graphIRI(name string) string {
+	return "<" + graphIRIPrefix + url.QueryEscape(name) + ">"
+}
+
+// parseGraphIRI reverses graphIRI.
+func parseGraphIRI(iri string) (string, error) {
+	return iriPayload(iri, graphIRIPrefix)
+}
+
+// nodeIRI renders n as a real IRIREF.
+func nodeIRI(n *node.Node) string {
+	return "<" + nodeIRIPrefix + url.QueryEscape(n.String()) + ">"
+}
+
+// parseNodeIRI reverses nodeIRI.
+func parseNodeIRI(iri string) (*node.Node, error) {
+	s, err := iriPayload(iri, nodeIRIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return node.Parse(s)
+}
+
+// predicateIRI renders p as a real IRIREF.
+func predicateIRI(p *predicate.Predicate) string {
+	return "<" + predicateIRIPrefix + url.QueryEscape(p.String()) + ">"
+}
+
+// parsePredicateIRI reverses predicateIRI.
+func parsePredicateIRI(iri string) (*predicate.Predicate, error) {
+	s, err := iriPayload(iri, predicateIRIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return predicate.Parse(s)
+}
+
+// iriPayload strips iri's surrounding "<" ">" and its expected prefix,
+// percent-decoding what is left.
+func iriPayload(iri, prefix string) (string, error) {
+	if !strings.HasPrefix(iri, "<") || !strings.HasSuffix(iri, ">") {
+		return "", fmt.Errorf("rdf: %q is not an IRIREF", iri)
+	}
+	iri = strings.TrimSuffix(strings.TrimPrefix(iri, "<"), ">")
+	if !strings.HasPrefix(iri, prefix) {
+		return "", fmt.Errorf("rdf: %q is not a %s IRI", iri, prefix)
+	}
+	return url.QueryUnescape(strings.TrimPrefix(iri, prefix))
+}
+
+// rdfObject renders a triple object as a real N-Triples term: an IRIREF for
+// a node or predicate object, or a quoted, typed literal.
+func rdfObject(o *triple.Object) (string, error) {
+	if n, err := o.Node(); err == nil {
+		return nodeIRI(n), nil
+	}
+	if p, err := o.Predicate(); err == nil {
+		return predicateIRI(p), nil
+	}
+	l, err := o.Literal()
+	if err != nil {
+		return "", fmt.Errorf("rdf: object %s is neither a node, predicate nor literal", o)
+	}
+	return encodeLiteral(l)
+}
+
+// encodeLiteral renders l as a real N-Triples typed literal: a quoted,
+// escaped lexical form plus a "^^<...>" datatype IRI under literalIRIPrefix.
+func encodeLiteral(l *literal.Literal) (string, error) {
+	var lexical string
+	switch l.Type() {
+	case literal.Bool:
+		v, _ := l.Bool()
+		lexical = strconv.FormatBool(v)
+	case literal.Int64:
+		v, _ := l.Int64()
+		lexical = strconv.FormatInt(v, 10)
+	case literal.Float64:
+		v, _ := l.Float64()
+		lexical = strconv.FormatFloat(v, 'g', -1, 64)
+	case literal.Text:
+		v, _ := l.Text()
+		lexical = v
+	case literal.Blob:
+		v, _ := l.Blob()
+		lexical = base64.StdEncoding.EncodeToString(v)
+	default:
+		return "", fmt.Errorf("rdf: literal %s has an unsupported type", l)
+	}
+
+	return `"` + escapeLiteral(lexical) + `"^^<` + literalIRIPrefix + l.Type().String() + ">", nil
+}
+
+// parseRDFObject parses a real N-Triples object term (IRIREF or typed
+// literal) back into a BadWolf object.
+func parseRDFObject(s string) (*triple.Object, error) {
+	if strings.HasPrefix(s, "<") {
+		if n, err := parseNodeIRI(s); err == nil {
+			return triple.NewNodeObject(n), nil
+		}
+		p, err := parsePredicateIRI(s)
+		if err != nil {
+			return nil, err
+		}
+		return triple.NewPredicateObject(p), nil
+	}
+
+	l, err := parseLiteral(s)
+	if err != nil {
+		return nil, err
+	}
+	return triple.NewLiteralObject(l), nil
+}
+
+// parseLiteral reverses encodeLiteral.
+func parseLiteral(s string) (*literal.Literal, error) {
+	idx := strings.LastIndex(s, `"^^<`)
+	if !strings.HasPrefix(s, `"`) || idx <= 0 || !strings.HasSuffix(s, ">") {
+		return nil, fmt.Errorf("rdf: %q is not a typed literal", s)
+	}
+	lexical := unescapeLiteral(s[1:idx])
+	datatype := strings.TrimSuffix(s[idx+len(`"^^<`):], ">")
+	typeName := strings.TrimPrefix(datatype, literalIRIPrefix)
+
+	b := literal.DefaultBuilder()
+	switch typeName {
+	case "bool":
+		v, err := strconv.ParseBool(lexical)
+		if err != nil {
+			return nil, err
+		}
+		return b.Build(literal.Bool, v)
+	case "int64":
+		v, err := strconv.ParseInt(lexical, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return b.Build(literal.Int64, v)
+	case "float64":
+		v, err := strconv.ParseFloat(lexical, 64)
+		if err != nil {
+			return nil, err
+		}
+		return b.Build(literal.Float64, v)
+	case "text":
+		return b.Build(literal.Text, lexical)
+	case "blob":
+		v, err := base64.StdEncoding.DecodeString(lexical)
+		if err != nil {
+			return nil, err
+		}
+		return b.Build(literal.Blob, v)
+	default:
+		return nil, fmt.Errorf("rdf: literal %q has unknown datatype %q", s, datatype)
+	}
+}
+
+var literalEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+// escapeLiteral escapes s per the N-Triples STRING_LITERAL_QUOTE grammar.
+func escapeLiteral(s string) string {
+	return literalEscaper.Replace(s)
+}
+
+var literalUnescaper = strings.NewReplacer(
+	`\n`, "\n",
+	`\r`, "\r",
+	`\t`, "\t",
+	`\"`, `"`,
+	`\\`, `\`,
+)
+
+// unescapeLiteral reverses escapeLiteral.
+func unescapeLiteral(s string) string {
+	return literalUnescaper.Replace(s)
+}
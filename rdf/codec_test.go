@@ -0,0 +1,74 @@
+package rdf
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSplitCodecFields(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{
+			line: `<s> <p> <o> .`,
+			want: []string{"<s>", "<p>", "<o>", "."},
+		},
+		{
+			line: `<s> <p> "my bucket" .`,
+			want: []string{"<s>", "<p>", `"my bucket"`, "."},
+		},
+		{
+			line: `<s> <p> "my bucket" <g> .`,
+			want: []string{"<s>", "<p>", `"my bucket"`, "<g>", "."},
+		},
+	}
+
+	for _, tt := range tests {
+		got := splitCodecFields(tt.line)
+		if len(got) != len(tt.want) {
+			t.Fatalf("splitCodecFields(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("splitCodecFields(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestParseNQuadsLineKeepsWhitespaceInLiteral(t *testing.T) {
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "my bucket"^^type:text`)
+
+	var buf bytes.Buffer
+	quadc := make(chan Quad, 1)
+	quadc <- Quad{Graph: "us-east-1", Triple: tr}
+	close(quadc)
+	if err := (nquadsCodec{}).EncodeQuads(context.Background(), &buf, quadc); err != nil {
+		t.Fatalf("EncodeQuads: %s", err)
+	}
+
+	q, err := parseNQuadsLine(strings.TrimSpace(buf.String()))
+	if err != nil {
+		t.Fatalf("parseNQuadsLine returned error: %s", err)
+	}
+
+	if got, want := q.Graph, "us-east-1"; got != want {
+		t.Fatalf("q.Graph = %q, want %q", got, want)
+	}
+	l, err := q.Triple.Object().Literal()
+	if err != nil {
+		t.Fatalf("q.Triple.Object().Literal(): %s", err)
+	}
+	if got, want := l.Interface(), "my bucket"; got != want {
+		t.Fatalf("literal = %q, want %q, the whitespace was lost", got, want)
+	}
+}
+
+func TestParseNQuadsLineRejectsMissingGraph(t *testing.T) {
+	if _, err := parseNQuadsLine(`<urn:awless:node:foo> <urn:awless:predicate:bar> "bucket"^^<urn:awless:literal:text> .`); err == nil {
+		t.Fatalf("expected an error for a missing graph term, got nil")
+	}
+}
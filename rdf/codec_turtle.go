@@ -0,0 +1,153 @@
+package rdf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/badwolf/triple"
+)
+
+func init() {
+	RegisterCodec("turtle", NewTurtleCodec(nil))
+}
+
+// TurtleCodec implements a restricted subset of Turtle: full IRIs plus
+// "prefix:local" compaction for any registered Prefixes, one statement per
+// line (no "a", no blank-node or collection syntax).
+type TurtleCodec struct {
+	// Prefixes maps a short prefix name to the IRI namespace it expands
+	// to, e.g. {"aws": "https://aws.amazon.com/"}.
+	Prefixes map[string]string
+}
+
+// NewTurtleCodec returns a TurtleCodec that compacts IRIs under prefixes
+// into "prefix:local" form on encode, and expands them back on decode. Pass
+// nil for no compaction.
+func NewTurtleCodec(prefixes map[string]string) *TurtleCodec {
+	return &TurtleCodec{Prefixes: prefixes}
+}
+
+func (c *TurtleCodec) Encode(ctx context.Context, w io.Writer, in <-chan *triple.Triple) error {
+	bw := bufio.NewWriter(w)
+	for prefix, ns := range c.Prefixes {
+		if _, err := fmt.Fprintf(bw, "@prefix %s: <%s> .\n", prefix, ns); err != nil {
+			return err
+		}
+	}
+	for t := range in {
+		o, err := rdfObject(t.Object())
+		if err != nil {
+			return err
+		}
+		line := fmt.Sprintf("%s %s %s .", c.compact(nodeIRI(t.Subject())), c.compact(predicateIRI(t.Predicate())), c.compact(o))
+		if _, err := fmt.Fprintln(bw, line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (c *TurtleCodec) Decode(ctx context.Context, r io.Reader, out chan<- *triple.Triple) error {
+	defer close(out)
+
+	prefixes := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "@prefix") {
+			name, ns, err := parseTurtlePrefix(line)
+			if err != nil {
+				return fmt.Errorf("rdf: invalid turtle prefix %q: %s", line, err)
+			}
+			prefixes[name] = ns
+			continue
+		}
+
+		t, err := parseTurtleLine(line, prefixes)
+		if err != nil {
+			return fmt.Errorf("rdf: invalid turtle statement %q: %s", line, err)
+		}
+		select {
+		case out <- t:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites iri to "prefix:local" when it falls under one of the
+// codec's registered namespaces, leaving it untouched otherwise.
+func (c *TurtleCodec) compact(term string) string {
+	if !strings.HasPrefix(term, "<") || !strings.HasSuffix(term, ">") {
+		return term
+	}
+	iri := strings.TrimSuffix(strings.TrimPrefix(term, "<"), ">")
+	for prefix, ns := range c.Prefixes {
+		if strings.HasPrefix(iri, ns) {
+			return prefix + ":" + strings.TrimPrefix(iri, ns)
+		}
+	}
+	return term
+}
+
+func parseTurtlePrefix(line string) (name, ns string, err error) {
+	fields := trimTrailingDot(splitCodecFields(line))
+	if len(fields) != 3 {
+		return "", "", fmt.Errorf("expected \"@prefix name: <iri>\"")
+	}
+	name = strings.TrimSuffix(fields[1], ":")
+	ns = strings.Trim(fields[2], "<>")
+	return name, ns, nil
+}
+
+// parseTurtleLine parses a single "subject predicate object ." statement,
+// expanding any "prefix:local" terms against prefixes before handing the
+// subject, predicate and object to their respective IRI/literal parsers. It
+// tokenizes with splitCodecFields rather than strings.Fields so a quoted
+// literal object containing whitespace isn't split mid-value.
+func parseTurtleLine(line string, prefixes map[string]string) (*triple.Triple, error) {
+	fields := trimTrailingDot(splitCodecFields(line))
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("expected subject, predicate and object, got %d terms", len(fields))
+	}
+	for i, field := range fields {
+		fields[i] = expandTurtlePrefix(field, prefixes)
+	}
+
+	s, err := parseNodeIRI(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	p, err := parsePredicateIRI(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	o, err := parseRDFObject(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return triple.New(s, p, o)
+}
+
+// expandTurtlePrefix rewrites a single "prefix:local" term back into its
+// full "<iri>" form, leaving terms that are already IRIREFs or literals
+// untouched.
+func expandTurtlePrefix(field string, prefixes map[string]string) string {
+	idx := strings.Index(field, ":")
+	if idx <= 0 || strings.HasPrefix(field, "<") || strings.HasPrefix(field, `"`) {
+		return field
+	}
+	prefix, local := field[:idx], field[idx+1:]
+	if ns, ok := prefixes[prefix]; ok {
+		return "<" + ns + local + ">"
+	}
+	return field
+}
@@ -0,0 +1,58 @@
+package rdf
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+)
+
+func TestTurtleCodecCompactsRegisteredPrefixes(t *testing.T) {
+	c := NewTurtleCodec(map[string]string{"aws": nodeIRIPrefix})
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+
+	triplec := make(chan *triple.Triple, 1)
+	triplec <- tr
+	close(triplec)
+
+	var buf bytes.Buffer
+	if err := c.Encode(context.Background(), &buf, triplec); err != nil {
+		t.Fatalf("Encode: %s", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "aws:") {
+		t.Fatalf("Encode() = %q, want it to compact the subject under the registered \"aws\" prefix", got)
+	}
+}
+
+func TestTurtleCodecRoundTrips(t *testing.T) {
+	c := NewTurtleCodec(map[string]string{"aws": nodeIRIPrefix})
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+
+	triplec := make(chan *triple.Triple, 1)
+	triplec <- tr
+	close(triplec)
+
+	var buf bytes.Buffer
+	if err := c.Encode(context.Background(), &buf, triplec); err != nil {
+		t.Fatalf("Encode: %s", err)
+	}
+
+	out := make(chan *triple.Triple)
+	errc := make(chan error, 1)
+	go func() { errc <- c.Decode(context.Background(), &buf, out) }()
+
+	var got []*triple.Triple
+	for tt := range out {
+		got = append(got, tt)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+
+	if len(got) != 1 || got[0].String() != tr.String() {
+		t.Fatalf("round-tripped triples = %v, want [%s]", got, tr)
+	}
+}
@@ -0,0 +1,64 @@
+package rdf
+
+import "strings"
+
+// splitCodecFields tokenizes a single N-Triples/N-Quads statement on
+// whitespace, except that anything wrapped in matching "<...>" or
+// "\"...\"" is kept as one token even if it contains spaces itself — so a
+// literal like "my bucket" (routine in the AWS tag/name values this
+// format carries) doesn't get truncated mid-value. Inside a quoted
+// literal, a backslash escapes the next character, so an escaped \" (as
+// escapeLiteral writes it) does not end the literal.
+func splitCodecFields(line string) []string {
+	var fields []string
+	var buf strings.Builder
+	var closing rune
+	var escaped bool
+
+	flush := func() {
+		if buf.Len() > 0 {
+			fields = append(fields, buf.String())
+			buf.Reset()
+		}
+	}
+
+	for _, r := range line {
+		if closing != 0 {
+			buf.WriteRune(r)
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && closing == '"':
+				escaped = true
+			case r == closing:
+				closing = 0
+			}
+			continue
+		}
+
+		switch r {
+		case '<':
+			buf.WriteRune(r)
+			closing = '>'
+		case '"':
+			buf.WriteRune(r)
+			closing = '"'
+		case ' ', '\t':
+			flush()
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	flush()
+
+	return fields
+}
+
+// trimTrailingDot drops the "." statement terminator N-Triples/N-Quads
+// lines end with, if present.
+func trimTrailingDot(fields []string) []string {
+	if n := len(fields); n > 0 && fields[n-1] == "." {
+		return fields[:n-1]
+	}
+	return fields
+}
@@ -4,17 +4,17 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
 	"math/rand"
+	"os"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/badwolf/storage"
-	"github.com/google/badwolf/storage/memory"
 	"github.com/google/badwolf/triple"
-	"github.com/google/badwolf/triple/literal"
 	"github.com/google/badwolf/triple/node"
 	"github.com/google/badwolf/triple/predicate"
+	"golang.org/x/sync/errgroup"
 )
 
 var parentOf *predicate.Predicate
@@ -29,16 +29,33 @@ func init() {
 type Graph struct {
 	storage.Graph
 	triplesCount int
+	rev          Revision
+	log          []revisionEntry
+	// store is the storage.Store this graph was created on. Operations
+	// that need the store rather than just the graph, such as Query's
+	// planner, read it from here instead of assuming the default store.
+	store storage.Store
+	// name is the graph's own name, stamped onto each statement when
+	// marshaling through a QuadCodec such as N-Quads.
+	name string
+}
+
+// NewNamedGraphWithBackend creates name on backend instead of the default
+// in-memory store, e.g. to open a disk-backed graph.
+func NewNamedGraphWithBackend(backend Backend, name string) (*Graph, error) {
+	g, err := backend.NewGraph(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	return &Graph{Graph: g, store: backend.Store(), name: name}, nil
 }
 
 func NewNamedGraph(name string) (*Graph, error) {
-	g, err := memory.DefaultStore.NewGraph(context.Background(), name)
-	return &Graph{Graph: g}, err
+	return NewNamedGraphWithBackend(defaultBackend, name)
 }
 
 func NewGraph() (*Graph, error) {
-	g, err := memory.DefaultStore.NewGraph(context.Background(), randString())
-	return &Graph{Graph: g}, err
+	return NewNamedGraphWithBackend(defaultBackend, randString())
 }
 
 func NewNamedGraphFromTriples(name string, triples []*triple.Triple) (*Graph, error) {
@@ -56,23 +73,23 @@ func NewGraphFromTriples(triples []*triple.Triple) (*Graph, error) {
 	return NewNamedGraphFromTriples(randString(), triples)
 }
 
-func NewNamedGraphFromFile(graphname, filepath string) (*Graph, error) {
-	data, err := ioutil.ReadFile(filepath)
+func NewNamedGraphFromFile(graphname, path string) (*Graph, error) {
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 
 	g, err := NewNamedGraph(graphname)
 	if err != nil {
 		return nil, err
 	}
 
-	err = g.Unmarshal(data)
-	if err != nil {
+	if err := g.UnmarshalFrom(context.Background(), f, sniffFormat(path)); err != nil {
 		return nil, err
 	}
 
-	return g, err
+	return g, nil
 }
 
 func NewGraphFromFile(filepath string) (*Graph, error) {
@@ -80,10 +97,29 @@ func NewGraphFromFile(filepath string) (*Graph, error) {
 }
 
 func (g *Graph) Add(triples ...*triple.Triple) error {
+	if err := g.AddTriples(context.Background(), triples); err != nil {
+		return err
+	}
 	g.triplesCount += len(triples)
-	return g.AddTriples(context.Background(), triples)
+	g.recordRevision(triples, false)
+	return nil
 }
 
+// Remove deletes triples from the graph, the counterpart to Add. Prefer
+// it over the embedded RemoveTriples so removals are reflected in
+// TriplesCount and in the revision log DiffSince reads from.
+func (g *Graph) Remove(triples ...*triple.Triple) error {
+	if err := g.RemoveTriples(context.Background(), triples); err != nil {
+		return err
+	}
+	g.triplesCount -= len(triples)
+	g.recordRevision(triples, true)
+	return nil
+}
+
+// VisitDepthFirst walks the graph depth-first from root along the
+// parent_of predicate only. For other relations, bounded concurrency, a
+// depth limit or early termination, use VisitBFS instead.
 func (g *Graph) VisitDepthFirst(root *node.Node, each func(*node.Node, int), distances ...int) error {
 	var dist int
 	if len(distances) > 0 {
@@ -92,20 +128,11 @@ func (g *Graph) VisitDepthFirst(root *node.Node, each func(*node.Node, int), dis
 
 	each(root, dist)
 
-	relations, err := triplesForSubjectAndPredicate(g, root, parentOf)
+	childs, err := g.childrenVia(root, parentOf)
 	if err != nil {
 		return err
 	}
 
-	var childs []*node.Node
-	for _, relation := range relations {
-		n, err := relation.Object().Node()
-		if err != nil {
-			return err
-		}
-		childs = append(childs, n)
-	}
-
 	sort.Sort(&nodeSorter{childs})
 
 	for _, child := range childs {
@@ -115,88 +142,136 @@ func (g *Graph) VisitDepthFirst(root *node.Node, each func(*node.Node, int), dis
 	return nil
 }
 
-func (g *Graph) copy() *Graph {
+func (g *Graph) copy(ctx context.Context) *Graph {
 	newg, err := NewGraph()
 	if err != nil {
 		panic(err)
 	}
 
-	all, _ := g.allTriples()
+	all, _ := g.allTriples(ctx)
 	newg.Add(all...)
 
 	return newg
 }
 
-func (g *Graph) Substract(other *Graph) *Graph {
-	sub := g.copy()
-
-	others, _ := other.allTriples()
-	sub.RemoveTriples(context.Background(), others)
-
-	return sub
+// Substract returns a new graph holding the triples of g that are not in
+// other. opts can pass WithMatchRules to drop triples matching ignore
+// rules (e.g. volatile timestamps) from the result. Substract copies g and
+// other in parallel and then removes other's triples from the copy of g in
+// one call; it does not read WithMaxConcurrency, so that option has no
+// effect here.
+func (g *Graph) Substract(ctx context.Context, other *Graph, opts ...Option) *Graph {
+	o := resolveOptions(opts)
+
+	var sub, otherCopy *Graph
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error { sub = g.copy(egctx); return nil })
+	eg.Go(func() error { otherCopy = other.copy(egctx); return nil })
+	eg.Wait()
+
+	others, _ := otherCopy.allTriples(ctx)
+	sub.Remove(others...)
+
+	return sub.filtered(ctx, o.rules)
 }
 
-func (g *Graph) Intersect(other *Graph) *Graph {
+// Intersect returns a new graph holding the triples present in both g and
+// other. opts can pass WithMatchRules to drop triples matching ignore
+// rules from the result, or WithMaxConcurrency to bound how many triples
+// are checked against other at once — the dominant cost on graphs with
+// hundreds of thousands of triples.
+func (g *Graph) Intersect(ctx context.Context, other *Graph, opts ...Option) *Graph {
+	o := resolveOptions(opts)
+
 	inter, err := NewGraph()
 	if err != nil {
 		panic(err)
 	}
 
-	all, err := g.allTriples()
+	all, err := g.allTriples(ctx)
 	if err != nil {
 		return nil
 	}
 
+	eg, egctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, o.concurrency)
+	var mu sync.Mutex
+
 	for _, tri := range all {
-		exists, err := other.Exist(context.Background(), tri)
-		if exists && err == nil {
-			inter.Add(tri)
-		}
+		tri := tri
+		eg.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-egctx.Done():
+				return egctx.Err()
+			}
+
+			exists, err := other.Exist(egctx, tri)
+			if err != nil || !exists {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			return inter.Add(tri)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil
+	}
+
+	return inter.filtered(ctx, o.rules)
+}
+
+// filtered returns g itself when rules is empty, otherwise removes every
+// triple rules.Match drops from g in place.
+func (g *Graph) filtered(ctx context.Context, rules MatchRules) *Graph {
+	if len(rules) == 0 {
+		return g
 	}
 
-	return inter
+	all, _ := g.allTriples(ctx)
+	for _, t := range all {
+		if rules.Match(t) {
+			g.Remove(t)
+		}
+	}
+	return g
 }
 
 func (g *Graph) TriplesCount() int {
 	return g.triplesCount
 }
 
-func (g *Graph) allTriples() ([]*triple.Triple, error) {
+func (g *Graph) allTriples(ctx context.Context) ([]*triple.Triple, error) {
 	var triples []*triple.Triple
-	errc := make(chan error)
 	triplec := make(chan *triple.Triple)
 
-	go func() {
-		defer close(errc)
-		errc <- g.Triples(context.Background(), triplec)
-	}()
+	eg, egctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return g.Triples(egctx, storage.DefaultLookup, triplec)
+	})
 
 	for t := range triplec {
 		triples = append(triples, t)
 	}
 
-	return triples, <-errc
+	return triples, eg.Wait()
 }
 
+// Unmarshal parses the native bql line format into the graph. Kept for
+// backward compatibility; new callers should prefer UnmarshalFrom, which
+// streams and supports other formats.
 func (g *Graph) Unmarshal(data []byte) error {
-	for _, line := range bytes.Split(data, []byte{'\n'}) {
-		if bytes.Equal(bytes.TrimSpace(line), []byte("")) {
-			continue
-		}
-		triple, err := triple.Parse(string(line), literal.DefaultBuilder())
-		if err != nil {
-			return err
-		}
-		if err = g.Add(triple); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return g.UnmarshalFrom(context.Background(), bytes.NewReader(data), "bql")
 }
 
+// Marshal renders the graph in the native bql line format, sorted for a
+// stable diff. Kept for backward compatibility; new callers should prefer
+// MarshalTo, which streams and supports other formats.
 func (g *Graph) Marshal() ([]byte, error) {
-	triples, err := g.allTriples()
+	triples, err := g.allTriples(context.Background())
 	if err != nil {
 		return nil, err
 	}
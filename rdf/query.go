@@ -0,0 +1,141 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/badwolf/bql/grammar"
+	"github.com/google/badwolf/bql/planner"
+	"github.com/google/badwolf/bql/semantic"
+	"github.com/google/badwolf/bql/table"
+)
+
+// QueryResult wraps the bound rows returned by a BQL query so callers do
+// not need to depend directly on badwolf's table package.
+type QueryResult struct {
+	table *table.Table
+}
+
+// Rows returns the bound rows of the query result, one per match.
+func (r *QueryResult) Rows() []table.Row {
+	if r.table == nil {
+		return nil
+	}
+	return r.table.Rows()
+}
+
+// NumRows returns the number of bound rows in the result.
+func (r *QueryResult) NumRows() int {
+	return len(r.Rows())
+}
+
+// Query parses and runs a BQL statement against the graph, returning the
+// bound rows. ctx is threaded through planning and execution so a caller
+// can cancel or time out a query that walks a large graph; parsing does
+// not take ctx.
+func (g *Graph) Query(ctx context.Context, bql string) (*QueryResult, error) {
+	stm := &semantic.Statement{}
+	p, err := grammar.NewParser(grammar.SemanticBQL())
+	if err != nil {
+		return nil, fmt.Errorf("rdf: could not build BQL parser: %s", err)
+	}
+	if err := p.Parse(grammar.NewLLk(bql, 1), stm); err != nil {
+		return nil, fmt.Errorf("rdf: invalid BQL statement: %s", err)
+	}
+
+	pln, err := planner.New(ctx, g.store, stm, 0, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rdf: could not plan query: %s", err)
+	}
+
+	tbl, err := pln.Execute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rdf: could not execute query: %s", err)
+	}
+
+	return &QueryResult{table: tbl}, nil
+}
+
+// triplePattern is a single subject/predicate/object pattern in a query.
+// Any field starting with "?" is a binding that is returned in the result.
+type triplePattern struct {
+	subject, predicate, object string
+}
+
+// QueryBuilder incrementally builds a BQL graph pattern so callers can ask
+// "give me all X reachable via P" without hand-writing BQL text.
+type QueryBuilder struct {
+	patterns []triplePattern
+	filters  []string
+	project  []string
+}
+
+// NewQuery starts a new query builder.
+func NewQuery() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Match adds a triple pattern to match. Use a "?name" placeholder for any
+// of subject, predicate or object to bind it as an output variable.
+func (b *QueryBuilder) Match(subject, predicate, object string) *QueryBuilder {
+	b.patterns = append(b.patterns, triplePattern{subject, predicate, object})
+	return b
+}
+
+// Filter adds a condition over previously bound variables to the BQL
+// having clause, e.g. "?depth <= 3". Several filters are joined with
+// "and".
+func (b *QueryBuilder) Filter(clause string) *QueryBuilder {
+	b.filters = append(b.filters, clause)
+	return b
+}
+
+// Project restricts the returned bindings to the given variable names. If
+// not called, every bound variable is returned.
+func (b *QueryBuilder) Project(vars ...string) *QueryBuilder {
+	b.project = append(b.project, vars...)
+	return b
+}
+
+// BQL renders the builder into the BQL text understood by the planner,
+// targeting the named graph in its FROM clause.
+func (b *QueryBuilder) BQL(graph string) string {
+	vars := b.project
+	if len(vars) == 0 {
+		vars = boundVars(b.patterns)
+	}
+
+	var clauses []string
+	for _, p := range b.patterns {
+		clauses = append(clauses, fmt.Sprintf("%s %s %s", p.subject, p.predicate, p.object))
+	}
+
+	stmt := fmt.Sprintf("select %s from %s where {%s}", strings.Join(vars, ", "), graph, strings.Join(clauses, " . "))
+	if len(b.filters) > 0 {
+		stmt += " having " + strings.Join(b.filters, " and ")
+	}
+	return stmt + ";"
+}
+
+// Run renders the builder and executes it against g.
+func (b *QueryBuilder) Run(ctx context.Context, g *Graph) (*QueryResult, error) {
+	return g.Query(ctx, b.BQL(g.name))
+}
+
+func boundVars(patterns []triplePattern) []string {
+	var vars []string
+	seen := make(map[string]bool)
+	add := func(field string) {
+		if strings.HasPrefix(field, "?") && !seen[field] {
+			seen[field] = true
+			vars = append(vars, field)
+		}
+	}
+	for _, p := range patterns {
+		add(p.subject)
+		add(p.predicate)
+		add(p.object)
+	}
+	return vars
+}
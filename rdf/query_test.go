@@ -0,0 +1,56 @@
+package rdf
+
+import (
+	"context"
+	"testing"
+)
+
+func TestQueryReturnsBoundRows(t *testing.T) {
+	g, err := NewNamedGraph("?querytest")
+	if err != nil {
+		t.Fatalf("NewNamedGraph: %s", err)
+	}
+
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+	if err := g.Add(tr); err != nil {
+		t.Fatalf("Add: %s", err)
+	}
+
+	res, err := g.Query(context.Background(), `select ?o from ?querytest where {/region<us-east-1> "has_name"@[] ?o};`)
+	if err != nil {
+		t.Fatalf("Query: %s", err)
+	}
+
+	if res.NumRows() != 1 {
+		t.Fatalf("NumRows() = %d, want 1", res.NumRows())
+	}
+
+	row := res.Rows()[0]
+	cell, ok := row["?o"]
+	if !ok {
+		t.Fatalf("row missing ?o binding, got %v", row)
+	}
+	if got, want := cell.String(), `"prod"^^type:text`; got != want {
+		t.Fatalf("?o = %q, want %q", got, want)
+	}
+}
+
+func TestQueryBuilderRun(t *testing.T) {
+	g, err := NewNamedGraph("?querybuildertest")
+	if err != nil {
+		t.Fatalf("NewNamedGraph: %s", err)
+	}
+
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+	if err := g.Add(tr); err != nil {
+		t.Fatalf("Add: %s", err)
+	}
+
+	res, err := NewQuery().Match(`/region<us-east-1>`, `"has_name"@[]`, "?o").Run(context.Background(), g)
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+	if res.NumRows() != 1 {
+		t.Fatalf("NumRows() = %d, want 1", res.NumRows())
+	}
+}
@@ -0,0 +1,54 @@
+package rdf
+
+import "github.com/google/badwolf/triple"
+
+// Revision is a monotonically increasing counter identifying a point in a
+// graph's change history. It is returned by Graph.Revision and
+// Graph.DiffSince, and consumed by DiffSince to compute incremental change
+// sets.
+//
+// DiffSince is kept apart by name from Graph.Diff(other *Graph, ...),
+// which is the rules-based graph-to-graph comparison.
+type Revision uint64
+
+// revisionEntry records a single triple add or remove at a given
+// revision, so DiffSince can replay the log instead of re-fetching the
+// whole graph.
+type revisionEntry struct {
+	rev       Revision
+	triple    *triple.Triple
+	tombstone bool
+}
+
+func (g *Graph) recordRevision(triples []*triple.Triple, tombstone bool) {
+	if len(triples) == 0 {
+		return
+	}
+	g.rev++
+	for _, t := range triples {
+		g.log = append(g.log, revisionEntry{rev: g.rev, triple: t, tombstone: tombstone})
+	}
+}
+
+// Revision returns the graph's current revision.
+func (g *Graph) Revision() Revision {
+	return g.rev
+}
+
+// DiffSince returns the triples added and removed since the given
+// revision, along with the graph's current revision. Callers use this to
+// persist incremental change sets rather than re-fetching and re-parsing
+// the full graph on every sync.
+func (g *Graph) DiffSince(since Revision) (added, removed []*triple.Triple, newRev Revision) {
+	for _, e := range g.log {
+		if e.rev <= since {
+			continue
+		}
+		if e.tombstone {
+			removed = append(removed, e.triple)
+		} else {
+			added = append(added, e.triple)
+		}
+	}
+	return added, removed, g.rev
+}
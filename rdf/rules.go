@@ -0,0 +1,247 @@
+package rdf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/badwolf/triple"
+	"golang.org/x/sync/errgroup"
+)
+
+// matchableStrings renders a triple's subject, predicate and object as the
+// plain, human-written values a rules file's glob patterns are meant to
+// match against — a node's pretty-printed form, a predicate's bare id
+// (without its "@[...]" time anchor), and a literal's bare value (without
+// its "^^type:..." tag) — rather than BadWolf's own wire-format rendering,
+// which would make patterns like "last_modified" or "prod" never match
+// anything.
+func matchableStrings(t *triple.Triple) (subject, predicate, object string) {
+	subject = t.Subject().String()
+	predicate = string(t.Predicate().ID())
+
+	o := t.Object()
+	if n, err := o.Node(); err == nil {
+		object = n.String()
+	} else if l, err := o.Literal(); err == nil {
+		object = fmt.Sprintf("%v", l.Interface())
+	} else {
+		object = o.String()
+	}
+	return subject, predicate, object
+}
+
+// MatchRule is a single glob pattern over a triple's subject, predicate
+// and object. A plain rule marks matching triples to be dropped; a rule
+// with Negate set force-keeps them even if an earlier rule dropped them.
+type MatchRule struct {
+	Subject, Predicate, Object string
+	Negate                     bool
+}
+
+// MatchRules is an ordered list of ignore rules, evaluated gitignore-style:
+// rules are applied in order and the last one to match a given triple
+// wins, so a trailing "!" rule can override an earlier broad exclusion.
+type MatchRules []MatchRule
+
+// Match reports whether t is dropped by rules.
+func (rules MatchRules) Match(t *triple.Triple) bool {
+	s, p, o := matchableStrings(t)
+
+	var drop bool
+	for _, r := range rules {
+		if !globMatch(r.Subject, s) || !globMatch(r.Predicate, p) || !globMatch(r.Object, o) {
+			continue
+		}
+		drop = !r.Negate
+	}
+	return drop
+}
+
+// globMatch reports whether pattern matches s, with "*" matching any run
+// of characters and "?" matching exactly one. Unlike path.Match, "/" is
+// treated as an ordinary character rather than a path separator, since
+// these patterns match triple terms like "/region<us-east-1>", not
+// filesystem paths.
+func globMatch(pattern, s string) bool {
+	re, err := regexp.Compile("^" + globToRegexp(pattern) + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// ParseMatchRules reads gitignore-style match rules from r: one rule per
+// line as "subject predicate object" glob patterns, blank lines and "#"
+// comments ignored, and a leading "!" negating the rule.
+func ParseMatchRules(r io.Reader) (MatchRules, error) {
+	var rules MatchRules
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var negate bool
+		if strings.HasPrefix(line, "!") {
+			negate = true
+			line = strings.TrimSpace(line[1:])
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("rdf: invalid match rule %q: expected \"subject predicate object\"", line)
+		}
+
+		rules = append(rules, MatchRule{Subject: fields[0], Predicate: fields[1], Object: fields[2], Negate: negate})
+	}
+
+	return rules, scanner.Err()
+}
+
+// LoadMatchRules reads match rules from path, so teams can share ignore
+// rules across machines.
+func LoadMatchRules(path string) (MatchRules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseMatchRules(f)
+}
+
+// defaultConcurrency bounds how many triples a graph operation compares
+// against another graph at once, when the caller doesn't pass
+// WithMaxConcurrency.
+const defaultConcurrency = 8
+
+// graphOpOptions holds the options Substract, Intersect and Diff accept.
+type graphOpOptions struct {
+	rules       MatchRules
+	concurrency int
+}
+
+// Option configures a graph operation such as Substract, Intersect or
+// Diff.
+type Option func(*graphOpOptions)
+
+// WithMatchRules filters the triples an operation considers through
+// rules, e.g. to ignore volatile predicates like timestamps when diffing.
+func WithMatchRules(rules MatchRules) Option {
+	return func(o *graphOpOptions) {
+		o.rules = rules
+	}
+}
+
+// WithMaxConcurrency bounds how many triples an operation compares
+// against the other graph at once. The default is defaultConcurrency.
+func WithMaxConcurrency(n int) Option {
+	return func(o *graphOpOptions) {
+		o.concurrency = n
+	}
+}
+
+func resolveOptions(opts []Option) graphOpOptions {
+	o := graphOpOptions{concurrency: defaultConcurrency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	return o
+}
+
+// Diff compares g against other and returns the triples added (present in
+// other but not g) and removed (present in g but not other), dropping any
+// triple matched by opts' WithMatchRules so volatile predicates don't show
+// up as noise in an `awless diff`. opts' WithMaxConcurrency bounds how many
+// Exist calls run in parallel against the other graph at once, the same
+// bottleneck Intersect bounds.
+func (g *Graph) Diff(ctx context.Context, other *Graph, opts ...Option) (added, removed []*triple.Triple, err error) {
+	o := resolveOptions(opts)
+
+	gTriples, err := g.allTriples(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	otherTriples, err := other.allTriples(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added, err = missingFrom(ctx, otherTriples, g, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed, err = missingFrom(ctx, gTriples, other, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return added, removed, nil
+}
+
+// missingFrom returns the triples of candidates that do not Exist in against,
+// and are not matched by o.rules, checking up to o.concurrency candidates in
+// parallel.
+func missingFrom(ctx context.Context, candidates []*triple.Triple, against *Graph, o graphOpOptions) ([]*triple.Triple, error) {
+	var missing []*triple.Triple
+
+	eg, egctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, o.concurrency)
+	var mu sync.Mutex
+
+	for _, t := range candidates {
+		t := t
+		eg.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-egctx.Done():
+				return egctx.Err()
+			}
+
+			exists, err := against.Exist(egctx, t)
+			if err != nil {
+				return err
+			}
+			if exists || o.rules.Match(t) {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			missing = append(missing, t)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
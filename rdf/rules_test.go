@@ -0,0 +1,61 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+)
+
+func mustParseTriple(t *testing.T, s string) *triple.Triple {
+	tr, err := triple.Parse(s, literal.DefaultBuilder())
+	if err != nil {
+		t.Fatalf("triple.Parse(%q): %s", s, err)
+	}
+	return tr
+}
+
+func TestMatchRulesDropAndNegate(t *testing.T) {
+	rules, err := ParseMatchRules(strings.NewReader(`
+# drop volatile timestamps everywhere
+*  last_modified  *
+
+# but keep them under critical/
+! /critical/*  *  *
+`))
+	if err != nil {
+		t.Fatalf("ParseMatchRules: %s", err)
+	}
+
+	dropped := mustParseTriple(t, `/region<us-east-1> "last_modified"@[] "2020-01-01"^^type:text`)
+	if !rules.Match(dropped) {
+		t.Fatalf("expected %s to be dropped", dropped)
+	}
+
+	kept := mustParseTriple(t, `/critical/region<us-east-1> "last_modified"@[] "2020-01-01"^^type:text`)
+	if rules.Match(kept) {
+		t.Fatalf("expected %s to be kept by the negated rule", kept)
+	}
+}
+
+func TestMatchRulesLaterRuleWins(t *testing.T) {
+	rules, err := ParseMatchRules(strings.NewReader(`
+! *  *  *
+*  *  *
+`))
+	if err != nil {
+		t.Fatalf("ParseMatchRules: %s", err)
+	}
+
+	tr := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+	if !rules.Match(tr) {
+		t.Fatalf("expected the later plain rule to override the earlier negation")
+	}
+}
+
+func TestParseMatchRulesRejectsBadLine(t *testing.T) {
+	if _, err := ParseMatchRules(strings.NewReader("only two")); err == nil {
+		t.Fatalf("expected an error for a line without exactly 3 fields")
+	}
+}
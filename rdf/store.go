@@ -0,0 +1,257 @@
+package rdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/badwolf/storage"
+	"github.com/google/badwolf/storage/memory"
+	"github.com/google/badwolf/triple"
+)
+
+// Store manages a set of named graphs sharing a single underlying BadWolf
+// storage.Store, so callers can enumerate and share graphs and apply
+// coordinated updates across several of them at once. The package-level
+// constructors (NewGraph, NewNamedGraph, ...) each silently pick
+// memory.DefaultStore and, for NewGraph, a random name; there is no way to
+// list what they created or update more than one graph together. Store is
+// for callers that need that, e.g. awless syncing several regions.
+type Store struct {
+	backend storage.Store
+}
+
+// NewStore wraps backend in a Store.
+func NewStore(backend storage.Store) *Store {
+	return &Store{backend: backend}
+}
+
+// DefaultStore is a Store over BadWolf's in-memory default store, the one
+// NewGraph/NewNamedGraph have always used.
+var DefaultStore = NewStore(memory.DefaultStore)
+
+// Graphs lists every named graph in the store.
+func (s *Store) Graphs(ctx context.Context) ([]*Graph, error) {
+	namec := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.backend.GraphNames(ctx, namec)
+	}()
+
+	var graphs []*Graph
+	for name := range namec {
+		g, err := s.Graph(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, g)
+	}
+
+	return graphs, <-errc
+}
+
+// Graph returns the existing named graph.
+func (s *Store) Graph(ctx context.Context, name string) (*Graph, error) {
+	g, err := s.backend.Graph(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Graph{Graph: g, store: s.backend, name: name}, nil
+}
+
+// NewGraph creates and returns a new named graph.
+func (s *Store) NewGraph(ctx context.Context, name string) (*Graph, error) {
+	g, err := s.backend.NewGraph(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Graph{Graph: g, store: s.backend, name: name}, nil
+}
+
+// DeleteGraph deletes the named graph and all its triples.
+func (s *Store) DeleteGraph(ctx context.Context, name string) error {
+	return s.backend.DeleteGraph(ctx, name)
+}
+
+// MarshalTo streams every graph in the store to w as a single document in
+// the named format, each statement addressed by the graph it came from.
+// Unlike Graph.MarshalTo, this lets several named graphs round-trip
+// through one file instead of collapsing into one; it requires a
+// QuadCodec, since a plain Codec has nowhere to carry the graph name.
+func (s *Store) MarshalTo(ctx context.Context, w io.Writer, format string) error {
+	c, err := codecFor(format)
+	if err != nil {
+		return err
+	}
+	qc, ok := c.(QuadCodec)
+	if !ok {
+		return fmt.Errorf("rdf: format %q does not address individual graphs, use Graph.MarshalTo", format)
+	}
+
+	graphs, err := s.Graphs(ctx)
+	if err != nil {
+		return err
+	}
+
+	quadc := make(chan Quad)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(quadc)
+		for _, g := range graphs {
+			triples, err := g.allTriples(ctx)
+			if err != nil {
+				errc <- err
+				return
+			}
+			for _, t := range triples {
+				select {
+				case quadc <- Quad{Graph: g.name, Triple: t}:
+				case <-ctx.Done():
+					errc <- ctx.Err()
+					return
+				}
+			}
+		}
+		errc <- nil
+	}()
+
+	if err := qc.EncodeQuads(ctx, w, quadc); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// UnmarshalFrom streams quads decoded from r into the named graphs they
+// address, creating each on first use. Unlike Graph.UnmarshalFrom, which
+// targets a single graph and discards the graph term a QuadCodec decodes,
+// this is what actually lets a multi-graph N-Quads document round-trip.
+func (s *Store) UnmarshalFrom(ctx context.Context, r io.Reader, format string) error {
+	c, err := codecFor(format)
+	if err != nil {
+		return err
+	}
+	qc, ok := c.(QuadCodec)
+	if !ok {
+		return fmt.Errorf("rdf: format %q does not address individual graphs, use Graph.UnmarshalFrom", format)
+	}
+
+	quadc := make(chan Quad)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- qc.DecodeQuads(ctx, r, quadc)
+	}()
+
+	graphs := make(map[string]*Graph)
+	for q := range quadc {
+		g, ok := graphs[q.Graph]
+		if !ok {
+			g, err = s.Graph(ctx, q.Graph)
+			if err != nil {
+				g, err = s.NewGraph(ctx, q.Graph)
+				if err != nil {
+					return err
+				}
+			}
+			graphs[q.Graph] = g
+		}
+		if err := g.Add(q.Triple); err != nil {
+			return err
+		}
+	}
+	return <-errc
+}
+
+// mutation is a single staged Add or Remove against a named graph.
+type mutation struct {
+	graph  string
+	add    []*triple.Triple
+	remove []*triple.Triple
+}
+
+// Tx accumulates Add/Remove mutations across one or more named graphs so
+// Store.Batch can apply them together.
+type Tx struct {
+	store *Store
+	muts  []mutation
+}
+
+// Add stages triples to be added to the named graph when the transaction
+// commits. No triple touches the graph until then.
+func (tx *Tx) Add(graph string, triples ...*triple.Triple) {
+	tx.muts = append(tx.muts, mutation{graph: graph, add: triples})
+}
+
+// Remove stages triples to be removed from the named graph when the
+// transaction commits.
+func (tx *Tx) Remove(graph string, triples ...*triple.Triple) {
+	tx.muts = append(tx.muts, mutation{graph: graph, remove: triples})
+}
+
+// Batch runs fn with a Tx that collects intended mutations, possibly
+// across several named graphs, and only calls AddTriples/RemoveTriples
+// once fn returns successfully and every referenced graph is confirmed to
+// exist — a mistake inside fn, or a reference to a graph that doesn't
+// exist, leaves every graph untouched. BadWolf's storage.Store has no
+// multi-graph transaction primitive of its own, so Batch fakes one: if a
+// mutation fails partway through committing, every mutation already
+// applied is reversed, in the reverse order it was applied, before the
+// error is returned — so a failure syncing, say, eu-west-1 after
+// us-east-1 already committed un-syncs us-east-1 again rather than
+// leaving the two regions split.
+func (s *Store) Batch(ctx context.Context, fn func(tx *Tx) error) error {
+	tx := &Tx{store: s}
+	if err := fn(tx); err != nil {
+		return fmt.Errorf("rdf: batch aborted: %s", err)
+	}
+
+	graphs := make(map[string]*Graph, len(tx.muts))
+	for _, m := range tx.muts {
+		if _, ok := graphs[m.graph]; ok {
+			continue
+		}
+		g, err := s.Graph(ctx, m.graph)
+		if err != nil {
+			return fmt.Errorf("rdf: batch references unknown graph %q: %s", m.graph, err)
+		}
+		graphs[m.graph] = g
+	}
+
+	var applied []mutation
+	for _, m := range tx.muts {
+		g := graphs[m.graph]
+
+		if len(m.add) > 0 {
+			if err := g.Add(m.add...); err != nil {
+				rollbackBatch(graphs, applied)
+				return fmt.Errorf("rdf: batch failed applying to %q, rolled back: %s", m.graph, err)
+			}
+			applied = append(applied, mutation{graph: m.graph, add: m.add})
+		}
+		if len(m.remove) > 0 {
+			if err := g.Remove(m.remove...); err != nil {
+				rollbackBatch(graphs, applied)
+				return fmt.Errorf("rdf: batch failed applying to %q, rolled back: %s", m.graph, err)
+			}
+			applied = append(applied, mutation{graph: m.graph, remove: m.remove})
+		}
+	}
+
+	return nil
+}
+
+// rollbackBatch reverses already-applied mutations against graphs, most
+// recent first, undoing an Add with a Remove and a Remove with an Add.
+// Best-effort: it is the closest thing to a true cross-graph rollback
+// that a non-transactional backend allows.
+func rollbackBatch(graphs map[string]*Graph, applied []mutation) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		m := applied[i]
+		g := graphs[m.graph]
+		if len(m.add) > 0 {
+			g.Remove(m.add...)
+		}
+		if len(m.remove) > 0 {
+			g.Add(m.remove...)
+		}
+	}
+}
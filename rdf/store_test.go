@@ -0,0 +1,123 @@
+package rdf
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/badwolf/storage/memory"
+	"github.com/google/badwolf/triple"
+)
+
+// TestRollbackBatchUndoesAppliedMutations exercises rollbackBatch directly:
+// given a record of what was already committed to each graph before a
+// later one failed, it must leave every graph exactly as it was
+// pre-batch, whether the undone step was an Add or a Remove.
+func TestRollbackBatchUndoesAppliedMutations(t *testing.T) {
+	s := NewStore(memory.DefaultStore)
+
+	a, err := s.NewGraph(context.Background(), randString())
+	if err != nil {
+		t.Fatalf("NewGraph a: %s", err)
+	}
+	b, err := s.NewGraph(context.Background(), randString())
+	if err != nil {
+		t.Fatalf("NewGraph b: %s", err)
+	}
+
+	existing := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+	if err := b.Add(existing); err != nil {
+		t.Fatalf("seeding b: %s", err)
+	}
+
+	added := mustParseTriple(t, `/region<eu-west-1> "has_name"@[] "staging"^^type:text`)
+	if err := a.Add(added); err != nil {
+		t.Fatalf("applying to a: %s", err)
+	}
+	if err := b.Remove(existing); err != nil {
+		t.Fatalf("applying to b: %s", err)
+	}
+
+	graphs := map[string]*Graph{a.name: a, b.name: b}
+	applied := []mutation{
+		{graph: a.name, add: []*triple.Triple{added}},
+		{graph: b.name, remove: []*triple.Triple{existing}},
+	}
+	rollbackBatch(graphs, applied)
+
+	aTriples, err := a.allTriples(context.Background())
+	if err != nil {
+		t.Fatalf("a.allTriples: %s", err)
+	}
+	if len(aTriples) != 0 {
+		t.Fatalf("graph %q was not rolled back, got %v", a.name, aTriples)
+	}
+
+	bTriples, err := b.allTriples(context.Background())
+	if err != nil {
+		t.Fatalf("b.allTriples: %s", err)
+	}
+	if len(bTriples) != 1 || bTriples[0].String() != existing.String() {
+		t.Fatalf("graph %q was not rolled back, got %v", b.name, bTriples)
+	}
+}
+
+func TestBatchAppliesAllMutationsOnSuccess(t *testing.T) {
+	s := NewStore(memory.DefaultStore)
+
+	a, err := s.NewGraph(context.Background(), randString())
+	if err != nil {
+		t.Fatalf("NewGraph a: %s", err)
+	}
+	b, err := s.NewGraph(context.Background(), randString())
+	if err != nil {
+		t.Fatalf("NewGraph b: %s", err)
+	}
+
+	toA := mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`)
+	toB := mustParseTriple(t, `/region<eu-west-1> "has_name"@[] "staging"^^type:text`)
+
+	err = s.Batch(context.Background(), func(tx *Tx) error {
+		tx.Add(a.name, toA)
+		tx.Add(b.name, toB)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch: %s", err)
+	}
+
+	aTriples, err := a.allTriples(context.Background())
+	if err != nil || len(aTriples) != 1 {
+		t.Fatalf("graph %q = %v, %v", a.name, aTriples, err)
+	}
+	bTriples, err := b.allTriples(context.Background())
+	if err != nil || len(bTriples) != 1 {
+		t.Fatalf("graph %q = %v, %v", b.name, bTriples, err)
+	}
+}
+
+func TestBatchAbortsBeforeTouchingAnyGraphOnFnError(t *testing.T) {
+	s := NewStore(memory.DefaultStore)
+
+	a, err := s.NewGraph(context.Background(), randString())
+	if err != nil {
+		t.Fatalf("NewGraph a: %s", err)
+	}
+
+	boom := errors.New("boom")
+	err = s.Batch(context.Background(), func(tx *Tx) error {
+		tx.Add(a.name, mustParseTriple(t, `/region<us-east-1> "has_name"@[] "prod"^^type:text`))
+		return boom
+	})
+	if err == nil {
+		t.Fatalf("expected Batch to propagate fn's error")
+	}
+
+	aTriples, err := a.allTriples(context.Background())
+	if err != nil {
+		t.Fatalf("a.allTriples: %s", err)
+	}
+	if len(aTriples) != 0 {
+		t.Fatalf("graph %q mutated despite fn returning an error, got %v", a.name, aTriples)
+	}
+}
@@ -0,0 +1,177 @@
+package rdf
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/badwolf/storage"
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrStopWalk can be returned by a VisitBFS visitor to stop the walk
+// early without that being treated as a failure.
+var ErrStopWalk = errors.New("rdf: stop walk")
+
+// Visitor is called once per node visited by VisitBFS, with the node's
+// distance from the root.
+type Visitor func(n *node.Node, depth int) error
+
+type walkOptions struct {
+	concurrency int
+	maxDepth    int
+	predicate   *predicate.Predicate
+}
+
+// WalkOption configures VisitBFS.
+type WalkOption func(*walkOptions)
+
+// WithConcurrency bounds how many nodes VisitBFS visits in parallel at
+// each depth. The default is 1 (sequential).
+func WithConcurrency(n int) WalkOption {
+	return func(o *walkOptions) { o.concurrency = n }
+}
+
+// WithMaxDepth stops the walk once it would go past depth d. The default,
+// 0, means unbounded.
+func WithMaxDepth(d int) WalkOption {
+	return func(o *walkOptions) { o.maxDepth = d }
+}
+
+// WithPredicate walks relations named p instead of the default parent_of.
+func WithPredicate(p *predicate.Predicate) WalkOption {
+	return func(o *walkOptions) { o.predicate = p }
+}
+
+func resolveWalkOptions(opts []WalkOption) walkOptions {
+	o := walkOptions{concurrency: 1, predicate: parentOf}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	return o
+}
+
+// VisitBFS walks the graph breadth-first from root, calling visit once
+// per node. Unlike VisitDepthFirst, which is hard-coded to the parent_of
+// predicate and a depth-first order, VisitBFS lets callers choose the
+// relation to follow (WithPredicate), bound how many nodes are visited in
+// parallel (WithConcurrency) and how deep the walk goes (WithMaxDepth),
+// and stop early by returning ErrStopWalk from visit.
+func (g *Graph) VisitBFS(ctx context.Context, root *node.Node, visit Visitor, opts ...WalkOption) error {
+	o := resolveWalkOptions(opts)
+
+	// stopped is set once a visitor returns ErrStopWalk. It, not
+	// ErrStopWalk itself, is what tells sibling goroutines at the same
+	// depth to wind down: feeding ErrStopWalk through errgroup as a real
+	// error would race it against a sibling's own context.Canceled from
+	// the egctx.Done() branch below, since errgroup only keeps whichever
+	// error is reported first.
+	var stopped int32
+
+	frontier := []*node.Node{root}
+	for depth := 0; len(frontier) > 0; depth++ {
+		if o.maxDepth > 0 && depth > o.maxDepth {
+			break
+		}
+
+		eg, egctx := errgroup.WithContext(ctx)
+		sem := make(chan struct{}, o.concurrency)
+
+		var mu sync.Mutex
+		var next []*node.Node
+
+		for _, n := range frontier {
+			n, depth := n, depth
+			eg.Go(func() error {
+				if atomic.LoadInt32(&stopped) != 0 {
+					return nil
+				}
+
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-egctx.Done():
+					return nil
+				}
+
+				if err := visit(n, depth); err != nil {
+					if err == ErrStopWalk {
+						atomic.StoreInt32(&stopped, 1)
+						return nil
+					}
+					return err
+				}
+
+				children, err := g.childrenVia(n, o.predicate)
+				if err != nil {
+					return err
+				}
+
+				mu.Lock()
+				next = append(next, children...)
+				mu.Unlock()
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if atomic.LoadInt32(&stopped) != 0 {
+			return nil
+		}
+
+		sort.Sort(&nodeSorter{next})
+		frontier = next
+	}
+
+	return nil
+}
+
+// childrenVia returns n's objects across every triple matching (n, p, ?),
+// shared by VisitDepthFirst and VisitBFS.
+func (g *Graph) childrenVia(n *node.Node, p *predicate.Predicate) ([]*node.Node, error) {
+	relations, err := triplesForSubjectAndPredicate(g, n, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []*node.Node
+	for _, relation := range relations {
+		child, err := relation.Object().Node()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+// triplesForSubjectAndPredicate streams every triple matching (s, p, ?),
+// the same drain-goroutine pattern allTriples uses.
+func triplesForSubjectAndPredicate(g *Graph, s *node.Node, p *predicate.Predicate) ([]*triple.Triple, error) {
+	var triples []*triple.Triple
+	triplec := make(chan *triple.Triple)
+
+	eg, egctx := errgroup.WithContext(context.Background())
+	eg.Go(func() error {
+		return g.TriplesForSubjectAndPredicate(egctx, s, p, storage.DefaultLookup, triplec)
+	})
+
+	for t := range triplec {
+		triples = append(triples, t)
+	}
+
+	return triples, eg.Wait()
+}
@@ -0,0 +1,61 @@
+package rdf
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/badwolf/triple/node"
+)
+
+func mustNode(t *testing.T, s string) *node.Node {
+	n, err := node.Parse(s)
+	if err != nil {
+		t.Fatalf("node.Parse(%q): %s", s, err)
+	}
+	return n
+}
+
+// TestVisitBFSStopWalkIsClean checks that a visitor returning ErrStopWalk
+// ends the walk with a nil error rather than a raw context error, repeated
+// over many runs with concurrency greater than 1. The graph is empty, so
+// the root is the only node visited.
+func TestVisitBFSStopWalkIsClean(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("NewGraph: %s", err)
+	}
+
+	root := mustNode(t, "/region<root>")
+
+	for attempt := 0; attempt < 50; attempt++ {
+		var visited int32
+		err := g.VisitBFS(context.Background(), root, func(n *node.Node, depth int) error {
+			atomic.AddInt32(&visited, 1)
+			return ErrStopWalk
+		}, WithConcurrency(8))
+
+		if err != nil {
+			t.Fatalf("attempt %d: VisitBFS returned %v, want nil (ErrStopWalk must not leak as a raw context error)", attempt, err)
+		}
+	}
+}
+
+func TestVisitBFSPropagatesVisitorError(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("NewGraph: %s", err)
+	}
+
+	root := mustNode(t, "/region<root>")
+	boom := errors.New("boom")
+
+	err = g.VisitBFS(context.Background(), root, func(n *node.Node, depth int) error {
+		return boom
+	}, WithConcurrency(4))
+
+	if err != boom {
+		t.Fatalf("VisitBFS returned %v, want %v", err, boom)
+	}
+}